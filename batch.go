@@ -0,0 +1,302 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/redis/go-redis/v9"
+)
+
+// atomicPutMultiLua performs an all-or-nothing CAS across KEYS: ARGV comes
+// in (oldEncoded, newEncoded, ttlSec) triples, one per key. An empty
+// oldEncoded means the key must not already exist, mirroring AtomicPut's
+// previous == nil convention. The not-found and value-changed error strings
+// match runScript's, and atomicPutMultiOn maps them to the same store
+// sentinel errors; "redis: key already exists" maps to store.ErrKeyExists.
+const atomicPutMultiLua = `
+for i = 1, #KEYS do
+	local old = ARGV[(i - 1) * 3 + 1]
+	local current = redis.call("GET", KEYS[i])
+
+	if old == "" then
+		if current then
+			return redis.error_reply("redis: key already exists")
+		end
+	elseif not current then
+		return redis.error_reply("redis: key is not found")
+	elseif current ~= old then
+		return redis.error_reply("redis: value has been changed")
+	end
+end
+
+for i = 1, #KEYS do
+	local new = ARGV[(i - 1) * 3 + 2]
+	local ttl = tonumber(ARGV[(i - 1) * 3 + 3])
+
+	if ttl > 0 then
+		redis.call("SET", KEYS[i], new, "EX", ttl)
+	else
+		redis.call("SET", KEYS[i], new)
+	end
+end
+
+return "OK"
+`
+
+var atomicPutMultiScript = redis.NewScript(atomicPutMultiLua)
+
+// GetMulti retrieves keys with a single MGET round trip (on Redis Cluster,
+// a pipeline of GETs instead; see getMultiCluster). The returned
+// slice has the same length and order as keys; an entry is nil when its
+// key is missing rather than failing the whole call.
+func (r *Store) GetMulti(ctx context.Context, keys []string, _ *store.ReadOptions) ([]*store.KVPair, error) {
+	nKeys := make([]string, len(keys))
+	for i, key := range keys {
+		nKeys[i] = normalize(key)
+	}
+
+	if r.cluster {
+		return r.getMultiCluster(ctx, r.client.(*redis.ClusterClient), keys, nKeys)
+	}
+
+	replies, err := r.client.MGet(ctx, nKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decodeGetMulti(keys, replies)
+}
+
+func (r *Store) decodeGetMulti(keys []string, replies []interface{}) ([]*store.KVPair, error) {
+	pairs := make([]*store.KVPair, len(keys))
+
+	for i, reply := range replies {
+		sreply, ok := reply.(string)
+		if !ok || sreply == "" {
+			continue
+		}
+
+		pair := &store.KVPair{}
+		if err := r.codec.Decode([]byte(sreply), pair); err != nil {
+			return nil, err
+		}
+		if pair.Key == "" {
+			pair.Key = keys[i]
+		}
+
+		pairs[i] = pair
+	}
+
+	return pairs, nil
+}
+
+// getMultiCluster mirrors mgetCluster: Redis Cluster only allows a
+// multi-key command when every key hashes to the same slot, so keys are
+// fetched individually through a pipeline that go-redis groups by node.
+func (r *Store) getMultiCluster(ctx context.Context, cc *redis.ClusterClient, keys, nKeys []string) ([]*store.KVPair, error) {
+	pipe := cc.Pipeline()
+
+	cmds := make([]*redis.StringCmd, len(nKeys))
+	for i, key := range nKeys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	pairs := make([]*store.KVPair, len(keys))
+	for i, cmd := range cmds {
+		val, err := cmd.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, err
+		}
+
+		pair := &store.KVPair{}
+		if err := r.codec.Decode(val, pair); err != nil {
+			return nil, err
+		}
+		if pair.Key == "" {
+			pair.Key = keys[i]
+		}
+
+		pairs[i] = pair
+	}
+
+	return pairs, nil
+}
+
+// PutMulti writes pairs through a single pipeline instead of one round
+// trip per key. Every pair shares opts' TTL, matching Put's semantics.
+func (r *Store) PutMulti(ctx context.Context, pairs []*store.KVPair, opts *store.WriteOptions) error {
+	expirationAfter := noExpiration
+	if opts != nil && opts.TTL != 0 {
+		expirationAfter = opts.TTL
+	}
+
+	pipe := r.client.Pipeline()
+
+	kvs := make([]*store.KVPair, len(pairs))
+	for i, pair := range pairs {
+		kv := &store.KVPair{
+			Key:       pair.Key,
+			Value:     pair.Value,
+			LastIndex: sequenceNum(),
+		}
+
+		valStr, err := r.codec.Encode(kv)
+		if err != nil {
+			return err
+		}
+
+		pipe.Set(ctx, normalize(pair.Key), valStr, expirationAfter)
+		kvs[i] = kv
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	for _, kv := range kvs {
+		nKey := normalize(kv.Key)
+		r.invalidateCache(nKey)
+		r.publishEvent(ctx, eventPut, nKey, kv.LastIndex)
+	}
+
+	return nil
+}
+
+// AtomicPutMultiOp is one operation in an AtomicPutMulti batch.
+type AtomicPutMultiOp struct {
+	Key      string
+	Value    []byte
+	Previous *store.KVPair
+}
+
+// AtomicPutMulti performs an all-or-nothing CAS across multiple keys in a
+// single Lua script invocation: either every op's precondition holds and
+// every key is written, or none are. Pass a nil Previous to require that
+// the key not already exist, same as AtomicPut.
+//
+// For Redis Cluster, ops are transparently grouped by hash tag before the
+// script runs, since a single invocation can only touch keys on one node;
+// ops sharing a hash tag (e.g. "{group}key1", "{group}key2") are
+// guaranteed to land in the same group. The all-or-nothing guarantee then
+// only holds within each hash-tag group, not across the whole batch: groups
+// run as separate script invocations, so if a later group fails, writes
+// already committed by earlier groups are not rolled back. Give every op in
+// a batch the same hash tag if the batch itself must be all-or-nothing on
+// Cluster.
+func (r *Store) AtomicPutMulti(ctx context.Context, ops []AtomicPutMultiOp, opts *store.WriteOptions) ([]*store.KVPair, error) {
+	if r.cluster {
+		return r.atomicPutMultiCluster(ctx, r.client.(*redis.ClusterClient), ops, opts)
+	}
+
+	return r.atomicPutMultiOn(ctx, r.client, ops, opts)
+}
+
+func (r *Store) atomicPutMultiOn(ctx context.Context, client redis.Scripter, ops []AtomicPutMultiOp, opts *store.WriteOptions) ([]*store.KVPair, error) {
+	expirationAfter := noExpiration
+	if opts != nil && opts.TTL != 0 {
+		expirationAfter = opts.TTL
+	}
+	ttlSec := int(expirationAfter / time.Second)
+
+	keys := make([]string, len(ops))
+	args := make([]interface{}, 0, len(ops)*3)
+	results := make([]*store.KVPair, len(ops))
+
+	for i, op := range ops {
+		newKV := &store.KVPair{Key: op.Key, Value: op.Value, LastIndex: sequenceNum()}
+
+		newVal, err := r.codec.Encode(newKV)
+		if err != nil {
+			return nil, err
+		}
+
+		var oldVal []byte
+		if op.Previous != nil {
+			oldVal, err = r.codec.Encode(op.Previous)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		keys[i] = normalize(op.Key)
+		args = append(args, string(oldVal), string(newVal), ttlSec)
+		results[i] = newKV
+	}
+
+	err := atomicPutMultiScript.Run(ctx, client, keys, args...).Err()
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "redis: key is not found"):
+			return nil, store.ErrKeyNotFound
+		case strings.Contains(err.Error(), "redis: value has been changed"):
+			return nil, store.ErrKeyModified
+		case strings.Contains(err.Error(), "redis: key already exists"):
+			return nil, store.ErrKeyExists
+		default:
+			return nil, err
+		}
+	}
+
+	for _, kv := range results {
+		nKey := normalize(kv.Key)
+		r.invalidateCache(nKey)
+		r.publishEvent(ctx, eventPut, nKey, kv.LastIndex)
+	}
+
+	return results, nil
+}
+
+func (r *Store) atomicPutMultiCluster(ctx context.Context, cc *redis.ClusterClient, ops []AtomicPutMultiOp, opts *store.WriteOptions) ([]*store.KVPair, error) {
+	groups := make(map[string][]int)
+	for i, op := range ops {
+		tag := hashTag(op.Key)
+		groups[tag] = append(groups[tag], i)
+	}
+
+	results := make([]*store.KVPair, len(ops))
+
+	for _, indices := range groups {
+		groupOps := make([]AtomicPutMultiOp, len(indices))
+		for j, idx := range indices {
+			groupOps[j] = ops[idx]
+		}
+
+		groupResults, err := r.atomicPutMultiOn(ctx, cc, groupOps, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, idx := range indices {
+			results[idx] = groupResults[j]
+		}
+	}
+
+	return results, nil
+}
+
+// hashTag extracts key's Redis Cluster hash tag (the substring between the
+// first "{" and the next "}"), or key itself when it has none. Ops sharing
+// a hash tag are guaranteed to hash to the same slot.
+func hashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+
+	return key[start+1 : start+1+end]
+}
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashTag(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "tagged key", key: "{user}profile", want: "user"},
+		{name: "plain key", key: "plainkey", want: "plainkey"},
+		{name: "unclosed brace", key: "no-close-brace{tag", want: "no-close-brace{tag"},
+		{name: "empty tag", key: "{}empty", want: "{}empty"},
+		{name: "tag not at start", key: "prefix{group}suffix", want: "group"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, hashTag(test.key))
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package redis
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheConfig enables an in-process read cache in front of a Store.
+type CacheConfig struct {
+	// MaxEntries bounds the number of cached keys. Zero means unbounded.
+	MaxEntries int
+
+	// MaxBytes bounds the total size of cached values. Zero means unbounded.
+	MaxBytes int64
+
+	// TTL bounds how long an entry is served from cache before it is
+	// re-fetched from Redis, regardless of invalidation. Zero disables
+	// the local TTL and relies solely on invalidation.
+	TTL time.Duration
+}
+
+// CacheStats reports the in-process cache's counters.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// Stats returns the in-process cache counters. It is the zero value when
+// Config.Cache was not set.
+func (r *Store) Stats() CacheStats {
+	if r.cache == nil {
+		return CacheStats{}
+	}
+
+	return CacheStats{
+		Hits:      atomic.LoadUint64(&r.cache.hits),
+		Misses:    atomic.LoadUint64(&r.cache.misses),
+		Evictions: atomic.LoadUint64(&r.cache.evictions),
+	}
+}
+
+func (r *Store) invalidateCache(key string) {
+	if r.cache != nil {
+		r.cache.invalidate(key)
+	}
+}
+
+func (r *Store) invalidateCachePrefix(prefix string) {
+	if r.cache != nil {
+		r.cache.invalidatePrefix(prefix)
+	}
+}
+
+// errTrackingRequiresSingleNode is returned by enableTracking when the
+// Store isn't backed by a single *redis.Client: CLIENT TRACKING ... REDIRECT
+// needs a dedicated connection to redirect to, which only a single-node
+// client can hand out via Conn.
+var errTrackingRequiresSingleNode = errors.New("redis: RESP3 client-side caching requires a single-node client")
+
+// enableTracking turns on RESP3 server-assisted invalidation so that
+// writes from other processes evict this process's cache too. It opens a
+// dedicated pub/sub connection that receives invalidation pushes on
+// __redis__:invalidate and redirects the main client's tracking there.
+//
+// *redis.Conn only exposes regular (non-pub/sub) commands, so the dedicated
+// connection used for CLIENT ID is not the one that ends up subscribed;
+// PSubscribe is only available on *redis.Client itself, which manages its
+// own dedicated connection for the lifetime of the subscription.
+func (r *Store) enableTracking(ctx context.Context) error {
+	client, ok := r.client.(*redis.Client)
+	if !ok {
+		return errTrackingRequiresSingleNode
+	}
+
+	conn := client.Conn()
+
+	id, err := conn.ClientID(ctx).Result()
+	if err != nil {
+		return err
+	}
+
+	sub := client.PSubscribe(ctx, "__redis__:invalidate")
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+
+	if err := conn.Do(ctx, "CLIENT", "TRACKING", "ON", "REDIRECT", strconv.FormatInt(id, 10)).Err(); err != nil {
+		_ = sub.Close()
+		return err
+	}
+
+	go r.trackInvalidations(sub)
+
+	return nil
+}
+
+func (r *Store) trackInvalidations(sub *redis.PubSub) {
+	for msg := range sub.Channel() {
+		if msg.Payload == "" {
+			// A nil payload means Redis is asking the client to flush
+			// everything it has tracked.
+			r.cache.invalidatePrefix("")
+			continue
+		}
+
+		r.cache.invalidate(msg.Payload)
+	}
+}
+
+type cacheEntry struct {
+	key       string
+	pair      *store.KVPair
+	size      int64
+	expiresAt time.Time
+}
+
+// cache is a small in-process LRU sitting in front of Get/Exists.
+type cache struct {
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+
+	maxEntries int
+	maxBytes   int64
+	usedBytes  int64
+	ttl        time.Duration
+
+	hits, misses, evictions uint64
+}
+
+func newCache(cfg *CacheConfig) *cache {
+	return &cache{
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		maxEntries: cfg.MaxEntries,
+		maxBytes:   cfg.MaxBytes,
+		ttl:        cfg.TTL,
+	}
+}
+
+func (c *cache) get(key string) (*store.KVPair, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	atomic.AddUint64(&c.hits, 1)
+
+	return entry.pair, true
+}
+
+func (c *cache) set(key string, pair *store.KVPair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	size := int64(len(pair.Value))
+
+	if el, ok := c.items[key]; ok {
+		old := el.Value.(*cacheEntry)
+		c.usedBytes += size - old.size
+		el.Value = c.newEntry(key, pair, size)
+		c.ll.MoveToFront(el)
+	} else {
+		el := c.ll.PushFront(c.newEntry(key, pair, size))
+		c.items[key] = el
+		c.usedBytes += size
+	}
+
+	c.evictLocked()
+}
+
+func (c *cache) newEntry(key string, pair *store.KVPair, size int64) *cacheEntry {
+	var expiresAt time.Time
+	if c.ttl != 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+
+	return &cacheEntry{key: key, pair: pair, size: size, expiresAt: expiresAt}
+}
+
+func (c *cache) evictLocked() {
+	for (c.maxEntries != 0 && c.ll.Len() > c.maxEntries) || (c.maxBytes != 0 && c.usedBytes > c.maxBytes) {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			return
+		}
+
+		c.removeElement(oldest)
+		atomic.AddUint64(&c.evictions, 1)
+	}
+}
+
+func (c *cache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// invalidatePrefix evicts every entry whose key starts with prefix; an
+// empty prefix evicts everything.
+func (c *cache) invalidatePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(el)
+		}
+	}
+}
+
+func (c *cache) removeElement(el *list.Element) {
+	entry := el.Value.(*cacheEntry)
+	c.ll.Remove(el)
+	delete(c.items, entry.key)
+	c.usedBytes -= entry.size
+}
+
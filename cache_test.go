@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheGetSetEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newCache(&CacheConfig{MaxEntries: 2})
+
+	c.set("a", &store.KVPair{Key: "a", Value: []byte("1")})
+	c.set("b", &store.KVPair{Key: "b", Value: []byte("2")})
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	_, ok := c.get("a")
+	assert.True(t, ok)
+
+	c.set("c", &store.KVPair{Key: "c", Value: []byte("3")})
+
+	_, ok = c.get("b")
+	assert.False(t, ok, "expected b to be evicted")
+
+	_, ok = c.get("a")
+	assert.True(t, ok, "expected a to remain cached")
+
+	_, ok = c.get("c")
+	assert.True(t, ok, "expected c to remain cached")
+}
+
+func TestCacheEvictsByMaxBytes(t *testing.T) {
+	c := newCache(&CacheConfig{MaxBytes: 4})
+
+	c.set("a", &store.KVPair{Key: "a", Value: []byte("1234")})
+	c.set("b", &store.KVPair{Key: "b", Value: []byte("5678")})
+
+	_, ok := c.get("a")
+	assert.False(t, ok, "expected a to be evicted to stay within MaxBytes")
+
+	_, ok = c.get("b")
+	assert.True(t, ok)
+}
+
+func TestCacheTTLExpiry(t *testing.T) {
+	c := newCache(&CacheConfig{TTL: time.Millisecond})
+
+	c.set("a", &store.KVPair{Key: "a"})
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := c.get("a")
+	assert.False(t, ok, "expected entry to expire after TTL")
+}
+
+func TestCacheStats(t *testing.T) {
+	c := newCache(&CacheConfig{})
+
+	c.set("a", &store.KVPair{Key: "a"})
+	c.get("a")
+	c.get("missing")
+
+	assert.EqualValues(t, 1, c.hits)
+	assert.EqualValues(t, 1, c.misses)
+}
+
+func TestCacheInvalidatePrefix(t *testing.T) {
+	c := newCache(&CacheConfig{})
+
+	c.set("dir/a", &store.KVPair{Key: "dir/a"})
+	c.set("dir/b", &store.KVPair{Key: "dir/b"})
+	c.set("other", &store.KVPair{Key: "other"})
+
+	c.invalidatePrefix("dir/")
+
+	_, ok := c.get("dir/a")
+	assert.False(t, ok)
+	_, ok = c.get("dir/b")
+	assert.False(t, ok)
+	_, ok = c.get("other")
+	assert.True(t, ok)
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientReportsClusterMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoints   []string
+		options     *Config
+		wantCluster bool
+	}{
+		{
+			name:        "single node",
+			endpoints:   []string{"localhost:6379"},
+			wantCluster: false,
+		},
+		{
+			name:        "multiple endpoints without explicit config",
+			endpoints:   []string{"localhost:7000", "localhost:7001"},
+			wantCluster: true,
+		},
+		{
+			name:        "explicit cluster config",
+			endpoints:   []string{"localhost:7000"},
+			options:     &Config{Cluster: &ClusterConfig{}},
+			wantCluster: true,
+		},
+		{
+			name:      "sentinel with ClusterClient is not Cluster mode",
+			endpoints: []string{"localhost:26379"},
+			options: &Config{
+				Sentinel: &Sentinel{MasterName: "mymaster", ClusterClient: true},
+			},
+			wantCluster: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, isCluster, err := newClient(test.endpoints, test.options)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = client.Close() })
+
+			assert.Equal(t, test.wantCluster, isCluster)
+
+			_, isClusterClientType := client.(*redis.ClusterClient)
+			if test.wantCluster {
+				assert.True(t, isClusterClientType)
+			}
+		})
+	}
+}
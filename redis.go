@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kvtools/valkeyrie"
@@ -54,8 +55,56 @@ type Config struct {
 	Password       string
 	DB             int
 	Sentinel       *Sentinel
+	Cluster        *ClusterConfig
 	PoolSize       int
 	MaxActiveConns int
+
+	// WatchBackend selects how Watch and WatchTree are notified of
+	// changes. Defaults to WatchBackendKeyspace.
+	WatchBackend WatchBackend
+
+	// Cache enables an in-process read cache in front of Get/Exists. Nil
+	// (the default) disables it.
+	Cache *CacheConfig
+}
+
+// WatchBackend selects the delivery mechanism behind Watch and WatchTree.
+type WatchBackend int
+
+const (
+	// WatchBackendKeyspace uses Redis keyspace notifications, the
+	// historical and default behavior: see the "glitch" note on Watch.
+	WatchBackendKeyspace WatchBackend = iota
+
+	// WatchBackendStreams records every mutation on a bounded Redis
+	// Stream and has Watch consume it with XREAD BLOCK and WatchTree with
+	// XREADGROUP BLOCK, so a
+	// watcher that reconnects resumes from the last delivered entry
+	// instead of silently missing updates.
+	WatchBackendStreams
+)
+
+// ClusterConfig holds the Redis Cluster configuration.
+// Setting it makes New build a *redis.ClusterClient instead of a
+// single-node one; it is also assumed whenever more than one endpoint is
+// given without Sentinel configured.
+//
+// Keys touched together by a single script invocation, such as the ones
+// used by AtomicPut/AtomicDelete and the lock they back, must map to the
+// same hash slot. Give them a shared hash tag, e.g. "{lockgroup}key1" and
+// "{lockgroup}key2", so Redis Cluster routes them to the same node.
+type ClusterConfig struct {
+	// RouteByLatency allows routing read-only commands to the closest master or replica node.
+	RouteByLatency bool
+
+	// RouteRandomly allows routing read-only commands to a random master or replica node.
+	RouteRandomly bool
+
+	// ReadOnly allows read-only commands on replica nodes.
+	ReadOnly bool
+
+	// MaxRedirects is the maximum number of retries before giving up on a MOVED/ASK redirect.
+	MaxRedirects int
 }
 
 // Sentinel holds the Redis Sentinel configuration.
@@ -94,9 +143,12 @@ func newStore(ctx context.Context, endpoints []string, options valkeyrie.Config)
 
 // Store implements the store.Store interface.
 type Store struct {
-	client redis.UniversalClient
-	script *redis.Script
-	codec  Codec
+	client       redis.UniversalClient
+	cluster      bool
+	script       *redis.Script
+	codec        Codec
+	watchBackend WatchBackend
+	cache        *cache
 }
 
 // New creates a new Redis client.
@@ -106,22 +158,48 @@ func New(ctx context.Context, endpoints []string, options *Config) (*Store, erro
 
 // NewWithCodec creates a new Redis client with codec config.
 func NewWithCodec(ctx context.Context, endpoints []string, options *Config, codec Codec) (*Store, error) {
-	client, err := newClient(endpoints, options)
+	client, isCluster, err := newClient(endpoints, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return makeStore(ctx, client, codec), nil
+	var watchBackend WatchBackend
+	var cacheConfig *CacheConfig
+	if options != nil {
+		watchBackend = options.WatchBackend
+		cacheConfig = options.Cache
+	}
+
+	s := makeStore(ctx, client, isCluster, codec, watchBackend, cacheConfig)
+
+	if cacheConfig != nil {
+		if err := s.enableTracking(ctx); err != nil {
+			log.Printf("redis: failed to enable client-side caching, falling back to local-only invalidation: %v", err)
+		}
+	}
+
+	return s, nil
 }
 
-func newClient(endpoints []string, options *Config) (redis.UniversalClient, error) {
+// newClient builds the underlying go-redis client and reports whether it is
+// a true Redis Cluster client. This is reported explicitly rather than left
+// to a type assertion on the result: NewFailoverClusterClient (Sentinel with
+// Sentinel.ClusterClient set) also returns a *redis.ClusterClient, but it
+// fronts a single replicated dataset, not Cluster's slot-sharded one, so it
+// must never be routed through the slot-aware fan-out paths.
+func newClient(endpoints []string, options *Config) (client redis.UniversalClient, isCluster bool, err error) {
+	endpoints, options, err = resolveEndpoints(endpoints, options)
+	if err != nil {
+		return nil, false, err
+	}
+
 	if options != nil && options.Sentinel != nil {
 		if options.Sentinel.MasterName == "" {
-			return nil, ErrMasterSetMustBeProvided
+			return nil, false, ErrMasterSetMustBeProvided
 		}
 
 		if !options.Sentinel.ClusterClient && (options.Sentinel.RouteByLatency || options.Sentinel.RouteRandomly) {
-			return nil, ErrInvalidRoutesOptions
+			return nil, false, ErrInvalidRoutesOptions
 		}
 
 		cfg := &redis.FailoverOptions{
@@ -146,14 +224,14 @@ func newClient(endpoints []string, options *Config) (redis.UniversalClient, erro
 		}
 
 		if options.Sentinel.ClusterClient {
-			return redis.NewFailoverClusterClient(cfg), nil
+			return redis.NewFailoverClusterClient(cfg), false, nil
 		}
 
-		return redis.NewFailoverClient(cfg), nil
+		return redis.NewFailoverClient(cfg), false, nil
 	}
 
-	if len(endpoints) > 1 {
-		return nil, ErrMultipleEndpointsUnsupported
+	if (options != nil && options.Cluster != nil) || len(endpoints) > 1 {
+		return newClusterClient(endpoints, options), true, nil
 	}
 
 	opt := &redis.Options{
@@ -172,11 +250,36 @@ func newClient(endpoints []string, options *Config) (redis.UniversalClient, erro
 		opt.MaxActiveConns = options.MaxActiveConns
 	}
 
-	// TODO: use *redis.ClusterClient if we support multiple endpoints.
-	return redis.NewClient(opt), nil
+	return redis.NewClient(opt), false, nil
 }
 
-func makeStore(ctx context.Context, client redis.UniversalClient, codec Codec) *Store {
+func newClusterClient(endpoints []string, options *Config) *redis.ClusterClient {
+	opt := &redis.ClusterOptions{
+		Addrs:        endpoints,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	if options != nil {
+		opt.TLSConfig = options.TLS
+		opt.Username = options.Username
+		opt.Password = options.Password
+		opt.PoolSize = options.PoolSize
+		opt.MaxActiveConns = options.MaxActiveConns
+
+		if options.Cluster != nil {
+			opt.RouteByLatency = options.Cluster.RouteByLatency
+			opt.RouteRandomly = options.Cluster.RouteRandomly
+			opt.ReadOnly = options.Cluster.ReadOnly
+			opt.MaxRedirects = options.Cluster.MaxRedirects
+		}
+	}
+
+	return redis.NewClusterClient(opt)
+}
+
+func makeStore(ctx context.Context, client redis.UniversalClient, isCluster bool, codec Codec, watchBackend WatchBackend, cacheConfig *CacheConfig) *Store {
 	// Listen to Keyspace events.
 	client.ConfigSet(ctx, "notify-keyspace-events", "KEA")
 
@@ -185,10 +288,18 @@ func makeStore(ctx context.Context, client redis.UniversalClient, codec Codec) *
 		c = codec
 	}
 
+	var ch *cache
+	if cacheConfig != nil {
+		ch = newCache(cacheConfig)
+	}
+
 	return &Store{
-		client: client,
-		script: redis.NewScript(luaScript()),
-		codec:  c,
+		client:       client,
+		cluster:      isCluster,
+		script:       redis.NewScript(luaScript()),
+		codec:        c,
+		watchBackend: watchBackend,
+		cache:        ch,
 	}
 }
 
@@ -199,11 +310,21 @@ func (r *Store) Put(ctx context.Context, key string, value []byte, opts *store.W
 		expirationAfter = opts.TTL
 	}
 
-	return r.setTTL(ctx, normalize(key), &store.KVPair{
+	nKey := normalize(key)
+	kv := &store.KVPair{
 		Key:       key,
 		Value:     value,
 		LastIndex: sequenceNum(),
-	}, expirationAfter)
+	}
+
+	if err := r.setTTL(ctx, nKey, kv, expirationAfter); err != nil {
+		return err
+	}
+
+	r.invalidateCache(nKey)
+	r.publishEvent(ctx, eventPut, nKey, kv.LastIndex)
+
+	return nil
 }
 
 func (r *Store) setTTL(ctx context.Context, key string, val *store.KVPair, ttl time.Duration) error {
@@ -217,7 +338,24 @@ func (r *Store) setTTL(ctx context.Context, key string, val *store.KVPair, ttl t
 
 // Get a value given its key.
 func (r *Store) Get(ctx context.Context, key string, _ *store.ReadOptions) (*store.KVPair, error) {
-	return r.get(ctx, normalize(key))
+	nKey := normalize(key)
+
+	if r.cache != nil {
+		if pair, ok := r.cache.get(nKey); ok {
+			return pair, nil
+		}
+	}
+
+	pair, err := r.get(ctx, nKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		r.cache.set(nKey, pair)
+	}
+
+	return pair, nil
 }
 
 func (r *Store) get(ctx context.Context, key string) (*store.KVPair, error) {
@@ -242,23 +380,50 @@ func (r *Store) get(ctx context.Context, key string) (*store.KVPair, error) {
 
 // Delete the value at the specified key.
 func (r *Store) Delete(ctx context.Context, key string) error {
-	return r.client.Del(ctx, normalize(key)).Err()
+	nKey := normalize(key)
+
+	if err := r.client.Del(ctx, nKey).Err(); err != nil {
+		return err
+	}
+
+	r.invalidateCache(nKey)
+	r.publishEvent(ctx, eventDelete, nKey, sequenceNum())
+
+	return nil
 }
 
 // Exists verify if a Key exists in the store.
 func (r *Store) Exists(ctx context.Context, key string, _ *store.ReadOptions) (bool, error) {
-	count, err := r.client.Exists(ctx, normalize(key)).Result()
+	nKey := normalize(key)
+
+	if r.cache != nil {
+		if _, ok := r.cache.get(nKey); ok {
+			return true, nil
+		}
+	}
+
+	count, err := r.client.Exists(ctx, nKey).Result()
 	return count != 0, err
 }
 
 // Watch for changes on a key.
-// glitch: we use notified-then-retrieve to retrieve *store.KVPair.
-// so the responses may sometimes inaccurate.
+// glitch: with the default WatchBackendKeyspace, we use notified-then-retrieve
+// to retrieve *store.KVPair, so the responses may sometimes be inaccurate.
+// Set Config.WatchBackend to WatchBackendStreams for reliable delivery.
 func (r *Store) Watch(ctx context.Context, key string, _ *store.ReadOptions) (<-chan *store.KVPair, error) {
+	if r.watchBackend == WatchBackendStreams {
+		return r.watchStream(ctx, normalize(key), false)
+	}
+
+	return r.watchKeyspace(ctx, key)
+}
+
+func (r *Store) watchKeyspace(ctx context.Context, key string) (<-chan *store.KVPair, error) {
 	watchCh := make(chan *store.KVPair)
 	nKey := normalize(key)
 
 	get := getter(func() (interface{}, error) {
+		r.invalidateCache(nKey)
 		pair, err := r.get(ctx, nKey)
 		if err != nil {
 			return nil, err
@@ -291,10 +456,19 @@ func (r *Store) Watch(ctx context.Context, key string, _ *store.ReadOptions) (<-
 
 // WatchTree watches for changes on child nodes under a given directory.
 func (r *Store) WatchTree(ctx context.Context, directory string, _ *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	if r.watchBackend == WatchBackendStreams {
+		return r.watchTreeStream(ctx, normalize(directory))
+	}
+
+	return r.watchTreeKeyspace(ctx, directory)
+}
+
+func (r *Store) watchTreeKeyspace(ctx context.Context, directory string) (<-chan []*store.KVPair, error) {
 	watchCh := make(chan []*store.KVPair)
 	nKey := normalize(directory)
 
 	get := getter(func() (interface{}, error) {
+		r.invalidateCachePrefix(nKey)
 		pair, err := r.list(ctx, nKey)
 		if err != nil {
 			return nil, err
@@ -369,6 +543,14 @@ func (r *Store) list(ctx context.Context, directory string) ([]*store.KVPair, er
 }
 
 func (r *Store) keys(ctx context.Context, regex string) ([]string, error) {
+	if r.cluster {
+		return r.keysCluster(ctx, r.client.(*redis.ClusterClient), regex)
+	}
+
+	return r.keysNode(ctx, r.client, regex)
+}
+
+func (r *Store) keysNode(ctx context.Context, client redis.UniversalClient, regex string) ([]string, error) {
 	const (
 		startCursor  = 0
 		endCursor    = 0
@@ -377,7 +559,7 @@ func (r *Store) keys(ctx context.Context, regex string) ([]string, error) {
 
 	var allKeys []string
 
-	keys, nextCursor, err := r.client.Scan(ctx, startCursor, regex, defaultCount).Result()
+	keys, nextCursor, err := client.Scan(ctx, startCursor, regex, defaultCount).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -385,7 +567,7 @@ func (r *Store) keys(ctx context.Context, regex string) ([]string, error) {
 	allKeys = append(allKeys, keys...)
 
 	for nextCursor != endCursor {
-		keys, nextCursor, err = r.client.Scan(ctx, nextCursor, regex, defaultCount).Result()
+		keys, nextCursor, err = client.Scan(ctx, nextCursor, regex, defaultCount).Result()
 		if err != nil {
 			return nil, err
 		}
@@ -400,13 +582,50 @@ func (r *Store) keys(ctx context.Context, regex string) ([]string, error) {
 	return allKeys, nil
 }
 
+// keysCluster fans the SCAN out to every master, since a Cluster node only
+// ever sees the keys living in its own slots.
+func (r *Store) keysCluster(ctx context.Context, cc *redis.ClusterClient, regex string) ([]string, error) {
+	var mu sync.Mutex
+	var allKeys []string
+
+	err := cc.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		keys, err := r.keysNode(ctx, master, regex)
+		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+			return err
+		}
+
+		mu.Lock()
+		allKeys = append(allKeys, keys...)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(allKeys) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return allKeys, nil
+}
+
 // mget values given their keys.
 func (r *Store) mget(ctx context.Context, directory string, keys ...string) ([]*store.KVPair, error) {
+	if r.cluster {
+		return r.mgetCluster(ctx, r.client.(*redis.ClusterClient), directory, keys...)
+	}
+
 	replies, err := r.client.MGet(ctx, keys...).Result()
 	if err != nil {
 		return nil, err
 	}
 
+	return r.decodeReplies(directory, keys, replies)
+}
+
+func (r *Store) decodeReplies(directory string, keys []string, replies []interface{}) ([]*store.KVPair, error) {
 	var pairs []*store.KVPair
 	for i, reply := range replies {
 		var sreply string
@@ -434,6 +653,35 @@ func (r *Store) mget(ctx context.Context, directory string, keys ...string) ([]*
 	return pairs, nil
 }
 
+// mgetCluster fetches keys individually through a pipeline rather than a
+// single MGET, since Redis Cluster rejects a multi-key command whose keys
+// don't all hash to the same slot; ClusterClient.Pipeline groups the
+// individual GETs by the node owning each key so this still costs one
+// round trip per master.
+func (r *Store) mgetCluster(ctx context.Context, cc *redis.ClusterClient, directory string, keys ...string) ([]*store.KVPair, error) {
+	pipe := cc.Pipeline()
+
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	replies := make([]interface{}, len(cmds))
+	for i, cmd := range cmds {
+		val, err := cmd.Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		replies[i] = val
+	}
+
+	return r.decodeReplies(directory, keys, replies)
+}
+
 // DeleteTree deletes a range of keys under a given directory.
 // glitch: we list all available keys first and then delete them all
 // it costs two operations on redis, so is not atomicity.
@@ -445,7 +693,15 @@ func (r *Store) DeleteTree(ctx context.Context, directory string) error {
 		return err
 	}
 
-	return r.client.Del(ctx, allKeys...).Err()
+	if err := r.client.Del(ctx, allKeys...).Err(); err != nil {
+		return err
+	}
+
+	nDirectory := normalize(directory)
+	r.invalidateCachePrefix(nDirectory)
+	r.publishEvent(ctx, eventDeleteTree, nDirectory, sequenceNum())
+
+	return nil
 }
 
 // AtomicPut is an atomic CAS operation on a single value.
@@ -469,12 +725,16 @@ func (r *Store) AtomicPut(ctx context.Context, key string, value []byte, previou
 		if err := r.setNX(ctx, nKey, newKV, expirationAfter); err != nil {
 			return false, nil, err
 		}
+		r.invalidateCache(nKey)
+		r.publishEvent(ctx, eventPut, nKey, newKV.LastIndex)
 		return true, newKV, nil
 	}
 
 	if err := r.cas(ctx, nKey, previous, newKV, formatSec(expirationAfter)); err != nil {
 		return false, nil, err
 	}
+	r.invalidateCache(nKey)
+	r.publishEvent(ctx, eventPut, nKey, newKV.LastIndex)
 	return true, newKV, nil
 }
 
@@ -501,15 +761,21 @@ func (r *Store) cas(ctx context.Context, key string, oldPair, newPair *store.KVP
 		return err
 	}
 
-	return r.runScript(ctx, cmdCAS, key, oldVal, newVal, secInStr)
+	return r.runScript(ctx, key, cmdCAS, oldVal, newVal, secInStr)
 }
 
 // AtomicDelete is an atomic delete operation on a single value
 // the value will be deleted if previous matched the one stored in db.
 func (r *Store) AtomicDelete(ctx context.Context, key string, previous *store.KVPair) (bool, error) {
-	if err := r.cad(ctx, normalize(key), previous); err != nil {
+	nKey := normalize(key)
+
+	if err := r.cad(ctx, nKey, previous); err != nil {
 		return false, err
 	}
+
+	r.invalidateCache(nKey)
+	r.publishEvent(ctx, eventDelete, nKey, sequenceNum())
+
 	return true, nil
 }
 
@@ -519,7 +785,7 @@ func (r *Store) cad(ctx context.Context, key string, old *store.KVPair) error {
 		return err
 	}
 
-	return r.runScript(ctx, cmdCAD, key, oldVal)
+	return r.runScript(ctx, key, cmdCAD, oldVal)
 }
 
 // Close the store connection.
@@ -527,8 +793,11 @@ func (r *Store) Close() error {
 	return r.client.Close()
 }
 
-func (r *Store) runScript(ctx context.Context, args ...interface{}) error {
-	err := r.script.Run(ctx, r.client, nil, args...).Err()
+// runScript executes the CAS/CAD script against key's slot: the key is
+// passed as KEYS[1] (rather than nil) so Redis Cluster routes the
+// invocation to the node owning it.
+func (r *Store) runScript(ctx context.Context, key string, args ...interface{}) error {
+	err := r.script.Run(ctx, r.client, []string{key}, args...).Err()
 	if err != nil && strings.Contains(err.Error(), "redis: key is not found") {
 		return store.ErrKeyNotFound
 	}
@@ -0,0 +1,297 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/redis/go-redis/v9"
+)
+
+// redlockDriftFactor and redlockClockDrift bound the clock drift assumed
+// between the nodes, per the Redlock algorithm
+// (https://redis.io/docs/manual/patterns/distributed-locks/#the-redlock-algorithm).
+const (
+	redlockDriftFactor = 0.01
+	redlockClockDrift  = 2 * time.Millisecond
+)
+
+// redlockReleaseScript deletes key only if its value still matches the
+// caller's token, so a lock can never be released by a node that doesn't
+// hold it.
+const redlockReleaseScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`
+
+// redlockRenewScript extends key's TTL only if its value still matches the
+// caller's token.
+const redlockRenewScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`
+
+var (
+	redlockRelease = redis.NewScript(redlockReleaseScript)
+	redlockRenew   = redis.NewScript(redlockRenewScript)
+)
+
+// ErrRedlockEndpointsRequired is thrown when NewRedlock is called without
+// any endpoints to run the algorithm against.
+var ErrRedlockEndpointsRequired = errors.New("redis: redlock requires at least one endpoint")
+
+// RedlockConfig configures a Redlock lock spanning N independent Redis
+// deployments. Endpoints must be independently operated instances: running
+// Redlock across nodes of the same Cluster or Sentinel group defeats the
+// algorithm's assumption that nodes fail independently.
+type RedlockConfig struct {
+	// Endpoints lists the independent Redis instances the lock runs against.
+	Endpoints []string
+
+	// Options configures the client built for each endpoint. Sentinel and
+	// Cluster are not meaningful here and are ignored.
+	Options *Config
+}
+
+// NewRedlock creates a store.Locker implementing the Redlock algorithm
+// across cfg.Endpoints. The returned Locker is not held and must be
+// acquired with .Lock. The Value option is ignored: Redlock's safety
+// relies on a token generated internally, not on caller-supplied content.
+func NewRedlock(key string, cfg *RedlockConfig, opts *store.LockOptions) (store.Locker, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, ErrRedlockEndpointsRequired
+	}
+
+	nodeOptions := nodeOptionsFor(cfg.Options)
+
+	nodes := make([]redis.UniversalClient, len(cfg.Endpoints))
+	for i, endpoint := range cfg.Endpoints {
+		client, _, err := newClient([]string{endpoint}, nodeOptions)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = client
+	}
+
+	ttl := defaultLockTTL
+	if opts != nil && opts.TTL != 0 {
+		ttl = opts.TTL
+	}
+
+	return &redlock{
+		nodes:    nodes,
+		key:      normalize(key),
+		ttl:      ttl,
+		quorum:   len(nodes)/2 + 1,
+		unlockCh: make(chan struct{}),
+	}, nil
+}
+
+type redlock struct {
+	nodes    []redis.UniversalClient
+	key      string
+	ttl      time.Duration
+	quorum   int
+	unlockCh chan struct{}
+	held     chan struct{}
+
+	mu    sync.Mutex
+	token string
+}
+
+func (l *redlock) Lock(ctx context.Context) (<-chan struct{}, error) {
+	if err := l.tryAcquire(ctx); err != nil {
+		return nil, err
+	}
+
+	lockHeld := make(chan struct{})
+	l.held = lockHeld
+	go l.holdLock(ctx, lockHeld)
+
+	return lockHeld, nil
+}
+
+// tryAcquire runs a single acquisition attempt: SET key token NX PX ttl
+// against every node in parallel, each bounded by a timeout well below
+// ttl. The lock is acquired iff a strict majority succeeds and the whole
+// attempt completed with enough of ttl left over to be useful.
+func (l *redlock) tryAcquire(ctx context.Context) error {
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	nodeTimeout := l.ttl / 10
+
+	start := time.Now()
+	successes := l.setOnNodes(ctx, token, nodeTimeout)
+	elapsed := time.Since(start)
+
+	drift := redlockDrift(l.ttl)
+
+	if successes < l.quorum || elapsed >= l.ttl-drift {
+		l.releaseOnNodes(context.Background(), token)
+		return store.ErrKeyExists
+	}
+
+	l.mu.Lock()
+	l.token = token
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *redlock) setOnNodes(ctx context.Context, token string, nodeTimeout time.Duration) int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(node redis.UniversalClient) {
+			defer wg.Done()
+
+			nctx, cancel := context.WithTimeout(ctx, nodeTimeout)
+			defer cancel()
+
+			ok, err := node.SetNX(nctx, l.key, token, l.ttl).Result()
+			if err == nil && ok {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}(node)
+	}
+
+	wg.Wait()
+
+	return successes
+}
+
+func (l *redlock) releaseOnNodes(ctx context.Context, token string) {
+	var wg sync.WaitGroup
+
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(node redis.UniversalClient) {
+			defer wg.Done()
+			redlockRelease.Run(ctx, node, []string{l.key}, token)
+		}(node)
+	}
+
+	wg.Wait()
+}
+
+func (l *redlock) holdLock(ctx context.Context, lockHeld chan struct{}) {
+	defer close(lockHeld)
+
+	heartbeat := time.NewTicker(l.ttl / 3)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-heartbeat.C:
+			if !l.renew(ctx) {
+				return
+			}
+		case <-l.unlockCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// renew reacquires majority via a PEXPIRE guarded by the held token,
+// abandoning the lock if a majority of nodes don't confirm.
+func (l *redlock) renew(ctx context.Context) bool {
+	l.mu.Lock()
+	token := l.token
+	l.mu.Unlock()
+
+	ttlMillis := strconv.FormatInt(l.ttl.Milliseconds(), 10)
+	nodeTimeout := l.ttl / 10
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	successes := 0
+
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(node redis.UniversalClient) {
+			defer wg.Done()
+
+			nctx, cancel := context.WithTimeout(ctx, nodeTimeout)
+			defer cancel()
+
+			res, err := redlockRenew.Run(nctx, node, []string{l.key}, token, ttlMillis).Int64()
+			if err == nil && res == 1 {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}(node)
+	}
+
+	wg.Wait()
+
+	return successes >= l.quorum
+}
+
+// Unlock signals holdLock to stop renewing and releases the lock on every
+// node. holdLock may have already returned on its own (e.g. it lost quorum
+// on a renewal), in which case nothing would ever receive from unlockCh; we
+// race the send against l.held, which holdLock closes on every exit path,
+// so Unlock can never block forever waiting for a heartbeat goroutine that
+// is already gone.
+func (l *redlock) Unlock(ctx context.Context) error {
+	select {
+	case l.unlockCh <- struct{}{}:
+	case <-l.held:
+	}
+
+	l.mu.Lock()
+	token := l.token
+	l.token = ""
+	l.mu.Unlock()
+
+	l.releaseOnNodes(ctx, token)
+
+	return nil
+}
+
+// nodeOptionsFor strips Sentinel and Cluster from options: each Redlock
+// endpoint is dialed as a single independent node regardless of what the
+// caller's Config otherwise describes.
+func nodeOptionsFor(options *Config) *Config {
+	if options == nil {
+		return nil
+	}
+
+	cfg := *options
+	cfg.Sentinel = nil
+	cfg.Cluster = nil
+
+	return &cfg
+}
+
+func redlockDrift(ttl time.Duration) time.Duration {
+	return time.Duration(float64(ttl)*redlockDriftFactor) + redlockClockDrift
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedlockRequiresEndpoints(t *testing.T) {
+	_, err := NewRedlock("key", &RedlockConfig{}, nil)
+	require.ErrorIs(t, err, ErrRedlockEndpointsRequired)
+}
+
+func TestNewRedlockQuorum(t *testing.T) {
+	tests := []struct {
+		endpoints []string
+		quorum    int
+	}{
+		{endpoints: []string{"127.0.0.1:16379"}, quorum: 1},
+		{endpoints: []string{"127.0.0.1:16379", "127.0.0.1:16380"}, quorum: 2},
+		{endpoints: []string{"127.0.0.1:16379", "127.0.0.1:16380", "127.0.0.1:16381"}, quorum: 2},
+		{endpoints: []string{"127.0.0.1:16379", "127.0.0.1:16380", "127.0.0.1:16381", "127.0.0.1:16382", "127.0.0.1:16383"}, quorum: 3},
+	}
+
+	for _, test := range tests {
+		locker, err := NewRedlock("key", &RedlockConfig{Endpoints: test.endpoints}, nil)
+		require.NoError(t, err)
+
+		rl, ok := locker.(*redlock)
+		require.True(t, ok)
+
+		assert.Equal(t, test.quorum, rl.quorum)
+	}
+}
+
+func TestRedlockDrift(t *testing.T) {
+	ttl := 10 * time.Second
+	want := time.Duration(float64(ttl)*redlockDriftFactor) + redlockClockDrift
+
+	assert.Equal(t, want, redlockDrift(ttl))
+}
+
+func TestRandomTokenIsUnique(t *testing.T) {
+	a, err := randomToken()
+	require.NoError(t, err)
+
+	b, err := randomToken()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, a)
+	assert.NotEqual(t, a, b)
+}
@@ -0,0 +1,165 @@
+package redis
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Recognized endpoint URI schemes. redis-sentinel:// and redis-cluster://
+// are valkeyrie-specific: they are rewritten to redis:// before being
+// handed to go-redis, so that a single DSN can select Sentinel or Cluster
+// mode without building a Config by hand.
+const (
+	schemeRedis    = "redis://"
+	schemeRedisTLS = "rediss://"
+	schemeUnix     = "unix://"
+	schemeSentinel = "redis-sentinel://"
+	schemeCluster  = "redis-cluster://"
+)
+
+func isURI(endpoint string) bool {
+	switch {
+	case strings.HasPrefix(endpoint, schemeRedis),
+		strings.HasPrefix(endpoint, schemeRedisTLS),
+		strings.HasPrefix(endpoint, schemeUnix),
+		strings.HasPrefix(endpoint, schemeSentinel),
+		strings.HasPrefix(endpoint, schemeCluster):
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveEndpoints rewrites any endpoint given as a connection URI into a
+// bare "host:port" address, merging the settings it carries (TLS,
+// credentials, DB, pool sizing, sentinel master name...) into a copy of
+// options. URI values take precedence over whatever options already set,
+// so a single opaque DSN from an env var or Docker secret is enough to
+// configure the store without building a Config by hand. Each of those
+// settings is copied from the parsed URI as is: one the URI leaves out
+// (e.g. TLS for a redis:// URI, or a password) is reset to its zero value
+// rather than kept from options.
+func resolveEndpoints(endpoints []string, options *Config) ([]string, *Config, error) {
+	merged := Config{}
+	if options != nil {
+		merged = *options
+	}
+
+	addrs := make([]string, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		if !isURI(endpoint) {
+			addrs = append(addrs, endpoint)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(endpoint, schemeSentinel):
+			addr, err := mergeSentinelURI(endpoint, &merged)
+			if err != nil {
+				return nil, nil, err
+			}
+			addrs = append(addrs, addr)
+
+		case strings.HasPrefix(endpoint, schemeCluster):
+			clusterAddrs, err := mergeClusterURI(endpoint, &merged)
+			if err != nil {
+				return nil, nil, err
+			}
+			addrs = append(addrs, clusterAddrs...)
+
+		default:
+			addr, err := mergeNodeURI(endpoint, &merged)
+			if err != nil {
+				return nil, nil, err
+			}
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs, &merged, nil
+}
+
+func mergeNodeURI(endpoint string, merged *Config) (string, error) {
+	opt, err := redis.ParseURL(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	merged.TLS = opt.TLSConfig
+	merged.Username = opt.Username
+	merged.Password = opt.Password
+	merged.DB = opt.DB
+	merged.PoolSize = opt.PoolSize
+	merged.MaxActiveConns = opt.MaxActiveConns
+
+	return opt.Addr, nil
+}
+
+func mergeClusterURI(endpoint string, merged *Config) ([]string, error) {
+	opt, err := redis.ParseClusterURL(schemeRedis + strings.TrimPrefix(endpoint, schemeCluster))
+	if err != nil {
+		return nil, err
+	}
+
+	if merged.Cluster == nil {
+		merged.Cluster = &ClusterConfig{}
+	}
+
+	merged.TLS = opt.TLSConfig
+	merged.Username = opt.Username
+	merged.Password = opt.Password
+	merged.PoolSize = opt.PoolSize
+	merged.MaxActiveConns = opt.MaxActiveConns
+
+	return opt.Addrs, nil
+}
+
+// mergeSentinelURI parses a redis-sentinel:// DSN. Its path means something
+// different than a plain redis:// URL's: it's the Sentinel master name, not
+// a DB index, so the path is blanked before delegating to redis.ParseURL for
+// everything else (TLS, credentials, pool sizing) and the master name and DB
+// index are recovered separately from the original path and "db" query
+// parameter.
+func mergeSentinelURI(endpoint string, merged *Config) (string, error) {
+	u, err := url.Parse(schemeRedis + strings.TrimPrefix(endpoint, schemeSentinel))
+	if err != nil {
+		return "", err
+	}
+
+	masterName := strings.Trim(u.Path, "/")
+	u.Path = ""
+
+	opt, err := redis.ParseURL(u.String())
+	if err != nil {
+		return "", err
+	}
+
+	if merged.Sentinel == nil {
+		merged.Sentinel = &Sentinel{}
+	}
+
+	if masterName != "" {
+		merged.Sentinel.MasterName = masterName
+	}
+
+	merged.TLS = opt.TLSConfig
+	merged.Username = opt.Username
+	merged.Password = opt.Password
+	merged.DB = opt.DB
+	merged.PoolSize = opt.PoolSize
+	merged.MaxActiveConns = opt.MaxActiveConns
+
+	if db := u.Query().Get("db"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return "", err
+		}
+		merged.DB = n
+	}
+
+	return opt.Addr, nil
+}
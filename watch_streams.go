@@ -0,0 +1,234 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// eventsStream is the bounded stream every mutating call appends to
+	// when Config.WatchBackend is WatchBackendStreams.
+	eventsStream = "__valkeyrie:events"
+
+	// eventsStreamMaxLen caps the stream so it doesn't grow unbounded.
+	// MAXLEN is applied with "~" so the trim is approximate and cheap.
+	eventsStreamMaxLen = 10000
+)
+
+// Event ops recorded on eventsStream.
+const (
+	eventPut        = "put"
+	eventDelete     = "delete"
+	eventDeleteTree = "deletetree"
+)
+
+// publishEvent records a mutation on eventsStream. It is a no-op unless
+// Config.WatchBackend is WatchBackendStreams, and a publish failure is
+// logged rather than returned: it must never fail the mutation it
+// describes.
+func (r *Store) publishEvent(ctx context.Context, op, key string, lastIndex uint64) {
+	if r.watchBackend != WatchBackendStreams {
+		return
+	}
+
+	err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: eventsStream,
+		MaxLen: eventsStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"op":        op,
+			"key":       key,
+			"lastIndex": lastIndex,
+		},
+	}).Err()
+	if err != nil {
+		log.Printf("redis: failed to publish %s event for %q: %v", op, key, err)
+	}
+}
+
+// watchStream is the WatchBackendStreams implementation of Watch. It reads
+// eventsStream with XREAD BLOCK starting from "$", filtering for the exact
+// key or, if withChildren, its prefix, and resumes from the last delivered
+// ID instead of losing events across a reconnect.
+func (r *Store) watchStream(ctx context.Context, key string, withChildren bool) (<-chan *store.KVPair, error) {
+	watchCh := make(chan *store.KVPair)
+
+	deliver := func() error {
+		pair, err := r.get(ctx, key)
+		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+			return err
+		}
+		if errors.Is(err, store.ErrKeyNotFound) {
+			pair = &store.KVPair{}
+		}
+		watchCh <- pair
+		return nil
+	}
+
+	go func() {
+		defer close(watchCh)
+
+		if err := deliver(); err != nil {
+			log.Printf("watchLoop in watchStream err: %v", err)
+			return
+		}
+
+		err := r.readEvents(ctx, "$", func(eventKey string) error {
+			if !matchesWatchKey(eventKey, key, withChildren) {
+				return nil
+			}
+			return deliver()
+		})
+		if err != nil {
+			log.Printf("watchLoop in watchStream err: %v", err)
+		}
+	}()
+
+	return watchCh, nil
+}
+
+// matchesWatchKey reports whether eventKey is the one Watch/WatchTree cares
+// about: an exact match, or (withChildren) anything under its prefix.
+func matchesWatchKey(eventKey, key string, withChildren bool) bool {
+	if withChildren {
+		return strings.HasPrefix(eventKey, key)
+	}
+	return eventKey == key
+}
+
+// watchTreeGroupName derives the consumer group name for a WatchTree call
+// on directory. It is deterministic rather than unique-per-call, so a
+// watcher that restarts rejoins the same group instead of starting a fresh
+// one at "$" and missing everything published during the restart. Only one
+// live WatchTree subscriber per directory is supported: Redis consumer
+// groups split delivery across their consumers, so a second concurrent
+// WatchTree call on the same directory would only see some of the events.
+func watchTreeGroupName(directory string) string {
+	return "valkeyrie-" + directory
+}
+
+// watchTreeStream is the WatchBackendStreams implementation of WatchTree.
+// The group is created once per directory (see watchTreeGroupName) and is
+// never destroyed on close, so a subsequent call resumes from wherever the
+// group last left off instead of missing events delivered while no watcher
+// was running.
+func (r *Store) watchTreeStream(ctx context.Context, directory string) (<-chan []*store.KVPair, error) {
+	watchCh := make(chan []*store.KVPair)
+
+	group := watchTreeGroupName(directory)
+	const consumer = "watcher"
+
+	if err := r.client.XGroupCreateMkStream(ctx, eventsStream, group, "$").Err(); err != nil &&
+		!strings.Contains(err.Error(), "BUSYGROUP") {
+		return nil, err
+	}
+
+	deliver := func() error {
+		pairs, err := r.list(ctx, directory)
+		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+			return err
+		}
+		if errors.Is(err, store.ErrKeyNotFound) {
+			pairs = nil
+		}
+		watchCh <- pairs
+		return nil
+	}
+
+	go func() {
+		defer close(watchCh)
+
+		if err := deliver(); err != nil {
+			log.Printf("watchLoop in watchTreeStream err: %v", err)
+			return
+		}
+
+		err := r.readGroupEvents(ctx, group, consumer, func(eventKey string) error {
+			if !strings.HasPrefix(eventKey, directory) {
+				return nil
+			}
+			return deliver()
+		})
+		if err != nil {
+			log.Printf("watchLoop in watchTreeStream err: %v", err)
+		}
+	}()
+
+	return watchCh, nil
+}
+
+// readEvents blocks on XREAD against eventsStream starting just after
+// lastID, invoking onEvent with the key of every delivered entry.
+func (r *Store) readEvents(ctx context.Context, lastID string, onEvent func(key string) error) error {
+	id := lastID
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		streams, err := r.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{eventsStream, id},
+			Block:   0,
+			Count:   100,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return err
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				id = msg.ID
+
+				key, _ := msg.Values["key"].(string)
+				if err := onEvent(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// readGroupEvents blocks on XREADGROUP against eventsStream as consumer in
+// group, acking every delivered entry once onEvent has run.
+func (r *Store) readGroupEvents(ctx context.Context, group, consumer string, onEvent func(key string) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{eventsStream, ">"},
+			Block:    0,
+			Count:    100,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return err
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				key, _ := msg.Values["key"].(string)
+
+				if err := onEvent(key); err != nil {
+					return err
+				}
+
+				r.client.XAck(ctx, eventsStream, group, msg.ID)
+			}
+		}
+	}
+}
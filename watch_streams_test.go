@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesWatchKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		eventKey     string
+		key          string
+		withChildren bool
+		want         bool
+	}{
+		{name: "exact match", eventKey: "foo/bar", key: "foo/bar", want: true},
+		{name: "exact mismatch", eventKey: "foo/baz", key: "foo/bar", want: false},
+		{name: "prefix match with children", eventKey: "foo/bar/baz", key: "foo/bar", withChildren: true, want: true},
+		{name: "prefix mismatch with children", eventKey: "foo/other", key: "foo/bar", withChildren: true, want: false},
+		{name: "exact key still matches with children", eventKey: "foo/bar", key: "foo/bar", withChildren: true, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, matchesWatchKey(test.eventKey, test.key, test.withChildren))
+		})
+	}
+}
+
+func TestWatchTreeGroupNameIsStable(t *testing.T) {
+	a := watchTreeGroupName("foo/bar")
+	b := watchTreeGroupName("foo/bar")
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, watchTreeGroupName("foo/baz"))
+}